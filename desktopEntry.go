@@ -12,20 +12,19 @@
 // will check your [desktopEntry.DesktopEntry.AppsPath] for a .desktop file, that matches your
 // [desktopEntry.DesktopEntry.Name], if it can't find it, it'll create a new one.
 // That will later on be updated it only when the binary path changes.
+// On Windows it installs a Start Menu .lnk shortcut instead, and on macOS a minimal .app
+// bundle under ~/Applications, both kept up to date using the same "executable path changed"
+// check.
 // See test [example].
 //
 // [example]: https://pkg.go.dev/github.com/mrf345/desktop-entry#example-DesktopEntry.Create
 package desktopEntry
 
 import (
-	"errors"
 	"fmt"
-	"io"
 	"io/fs"
 	"os"
-	"os/exec"
 	"path/filepath"
-	"regexp"
 	"runtime"
 	"slices"
 	"strings"
@@ -36,8 +35,18 @@ type DesktopEntry struct {
 	Name string
 	// Application version (required)
 	Version string
-	// Application .png icon data (required)
+	// Application .png icon data, converted to .ico on Windows and .icns on macOS. On Linux
+	// it is only used, as a single icon file, when Icons and IconSVG are both empty
+	// (required, except on Linux when Icons or IconSVG is set)
 	Icon []byte
+	// Sized .png icon data keyed by pixel size (16, 22, 24, 32, 48, 64, 128, 256, 512),
+	// installed into the freedesktop Icon Theme layout under
+	// ~/.local/share/icons/hicolor/<size>x<size>/apps. A single entry has the other
+	// standard sizes backfilled by downscaling it (default: nil)
+	Icons map[int][]byte
+	// Scalable .svg icon data, installed under
+	// ~/.local/share/icons/hicolor/scalable/apps (default: nil)
+	IconSVG []byte
 	// Executable type (default: Application)
 	Type string
 	// Semicolon separated list of categories (default: '')
@@ -52,7 +61,7 @@ type DesktopEntry struct {
 	IconsPath string
 	// Default permission for created files and directories (default: 0776)
 	Perm fs.FileMode
-	// Supported operating systems (default: []string{"linux"})
+	// Supported operating systems (default: []string{"linux", "windows", "darwin"})
 	OSs []string
 	// Update if executable path has changed (default: true)
 	UpdateIfChanged bool
@@ -60,6 +69,57 @@ type DesktopEntry struct {
 	RerunIfChanged bool
 	// Mime type to associate the desktop entry with
 	MimeType MimeType
+	// Install as a systemd user service or an XDG autostart entry in addition to the
+	// plain launcher, Linux only
+	Service Service
+	// Generic name shown alongside Name in some launchers, e.g. "Web Browser" (default: '')
+	GenericName string
+	// Search terms, rendered as a semicolon separated Keywords= list (default: nil)
+	Keywords []string
+	// Run Exec inside a terminal emulator (default: false)
+	Terminal bool
+	// Hide the entry from menus while keeping it runnable, e.g. for a helper launched by
+	// another app (default: false)
+	NoDisplay bool
+	// Mark the entry as deleted, overrides a lower priority entry of the same name without
+	// removing it (default: false)
+	Hidden bool
+	// Restrict display to these desktop environments, e.g. []string{"GNOME"} (default: nil)
+	OnlyShowIn []string
+	// Hide from these desktop environments (default: nil)
+	NotShowIn []string
+	// Path to a binary that must be present and executable for the entry to be shown
+	// (default: '')
+	TryExec string
+	// Advertise that the app only ever opens a single, main window (default: false)
+	SingleMainWindow bool
+	// Advertise that the app prefers the system's non-default (discrete) GPU (default: false)
+	PrefersNonDefaultGPU bool
+	// Advertise that the app can be activated over D-Bus (default: false)
+	DBusActivatable bool
+	// Target URL, only used when Type is "Link" (default: '')
+	URL string
+	// Extra [Desktop Action] entries, e.g. right click menu items (default: nil)
+	Actions []Action
+	// Per-locale overrides for Name, GenericName, Comment and Keywords, keyed by locale
+	// code, e.g. Locales["de"] = Locale{Name: "..."} renders as Name[de]=... (default: nil)
+	Locales map[string]Locale
+}
+
+// Action describes a single [Desktop Action], shown by launchers as a secondary action on the
+// entry, e.g. a right click menu item
+type Action struct {
+	Name string
+	Exec string
+	Icon string
+}
+
+// Locale holds the translated fields for a single locale code
+type Locale struct {
+	Name        string
+	GenericName string
+	Comment     string
+	Keywords    []string
 }
 
 type MimeType struct {
@@ -70,6 +130,20 @@ type MimeType struct {
 	Patterns    []string
 }
 
+type Service struct {
+	// Install and start a systemd --user unit (default: false)
+	Enabled bool
+	// Drop the desktop entry into ~/.config/autostart instead, ignored when Enabled is
+	// true (default: false)
+	AutoStart bool
+	// systemd Restart= value, e.g. "on-failure" (default: '')
+	Restart string
+	// systemd [Install] WantedBy= value (default: default.target)
+	WantedBy string
+	// systemd unit Description= (default: DesktopEntry.Comment)
+	Description string
+}
+
 // Create a new [desktopEntry.DesktopEntry] instance with the default options
 func New(name, version string, icon []byte) *DesktopEntry {
 	return &DesktopEntry{
@@ -81,15 +155,19 @@ func New(name, version string, icon []byte) *DesktopEntry {
 		Perm:            0755,
 		AppsPath:        fmt.Sprintf("%s/.local/share/applications", os.Getenv("HOME")),
 		IconsPath:       fmt.Sprintf("%s/.icons", os.Getenv("HOME")),
-		OSs:             []string{"linux"},
+		OSs:             []string{"linux", "windows", "darwin"},
 		UpdateIfChanged: true,
 		RerunIfChanged:  true,
 	}
 }
 
-// Creates a new desktop entry or updates an existing one if the executable paths mismatch
+// Creates a new desktop entry, or its platform equivalent, or updates an existing one if the
+// executable path has changed. It also persists a manifest of every file it writes, so a later
+// Create can clean up anything that's no longer part of the configuration (e.g. a dropped
+// MimeType) and [desktopEntry.DesktopEntry.Remove] can delete them without guessing paths
 func (de *DesktopEntry) Create() (err error) {
 	var changed bool
+	var paths, platformPaths, servicePaths []string
 
 	isDevBuild := strings.HasPrefix(os.Args[0], os.TempDir())
 	isSupportedOs := slices.Contains(de.OSs, runtime.GOOS)
@@ -104,167 +182,106 @@ func (de *DesktopEntry) Create() (err error) {
 		return
 	}
 
-	if err = de.createIcon(); err != nil {
-		err = fmt.Errorf("failed to create icon file > %w", err)
-		return
-	}
-
-	if changed, err = de.createEntry(); err != nil {
-		err = fmt.Errorf("failed to create or update desktop entry file > %w", err)
+	if changed, platformPaths, err = de.createPlatformEntry(); err != nil {
+		err = fmt.Errorf("failed to create or update desktop entry > %w", err)
 		return
 	}
+	paths = append(paths, platformPaths...)
 
 	if shouldCreateMimeType {
 		if err = de.createMimeType(); err != nil {
 			err = fmt.Errorf("failed to create mime type file > %w", err)
 			return
 		}
-	}
 
-	if changed && de.RerunIfChanged {
-		err = restart()
-	}
-
-	return
-}
-
-func (de DesktopEntry) createPaths() (err error) {
-	paths := []string{de.AppsPath, de.IconsPath}
-
-	if len(de.MimeType.Path) > 0 {
-		paths = append(paths, de.MimeType.Path)
-	}
-
-	for _, path := range paths {
-		if _, err = os.Stat(path); os.IsNotExist(err) {
-			if err = os.MkdirAll(path, de.Perm); err != nil {
-				return
-			}
-			err = nil
-		} else if err != nil {
+		var mimePath string
+		if mimePath, err = de.getMimeTypePath(); err != nil {
 			return
 		}
+		paths = append(paths, mimePath)
 	}
 
-	return
-}
-
-func (de DesktopEntry) createIcon() (err error) {
-	var iconPath = de.getIconPath()
-
-	if _, err = os.Stat(iconPath); !os.IsNotExist(err) {
-		return
-	}
-
-	return os.WriteFile(iconPath, de.Icon, de.Perm)
-}
-
-func (de DesktopEntry) getIconPath() string {
-	return filepath.Join(de.IconsPath, de.getID()+".png")
-}
-
-func (de DesktopEntry) getID() string {
-	return strings.ToLower(de.Name)
-}
-
-func (de DesktopEntry) createEntry() (changed bool, err error) {
-	var entryPath = filepath.Join(de.AppsPath, de.getID()+".desktop")
-	var entryData string
-	var doUpdate = de.UpdateIfChanged
-
-	if _, err = os.Stat(entryPath); err != nil && !os.IsNotExist(err) {
-		return
-	}
-
-	if _, err = os.Stat(entryPath); err == nil && doUpdate {
-		if doUpdate, err = de.shouldUpdate(entryPath); err != nil {
+	if de.Service.Enabled || de.Service.AutoStart {
+		if _, servicePaths, err = de.createService(); err != nil {
+			err = fmt.Errorf("failed to install service > %w", err)
 			return
 		}
-	} else if !os.IsNotExist(err) {
-		return
+		paths = append(paths, servicePaths...)
 	}
 
-	if doUpdate {
-		if entryData, err = de.getEntryContent(); err != nil {
-			return
-		}
-
-		changed = true
-		err = os.WriteFile(entryPath, []byte(entryData), de.Perm)
-		_ = exec.Command(fmt.Sprintf(`update-desktop-database "%s"`, de.AppsPath)).Run()
+	if err = de.syncManifest(paths); err != nil {
+		err = fmt.Errorf("failed to update state manifest > %w", err)
 		return
 	}
 
+	if changed && de.RerunIfChanged {
+		err = restart()
+	}
+
 	return
 }
 
-func (de DesktopEntry) shouldUpdate(entryPath string) (yes bool, err error) {
-	var entryFile *os.File
-	var execRegex, classRegex *regexp.Regexp
-	var existingData []byte
-	var execLine string
+// Remove deletes every file the last [desktopEntry.DesktopEntry.Create] produced, using its
+// manifest to find them rather than re-deriving paths from the current (possibly
+// since-changed) settings. A systemd user unit installed through Service.Enabled has its
+// unit file deleted but is not stopped or disabled
+func (de *DesktopEntry) Remove() (err error) {
+	var m manifest
 
-	if execRegex, err = regexp.Compile("Exec=sh -c '.*'"); err != nil {
+	if m, err = loadManifest(de.getID()); err != nil {
 		return
 	}
 
-	if classRegex, err = regexp.Compile("StartupWMClass=.*"); err != nil {
-		return
+	for _, path := range m.Files {
+		if rmErr := os.RemoveAll(path); rmErr != nil {
+			err = rmErr
+		}
 	}
 
-	if entryFile, err = os.Open(entryPath); err != nil {
-		return
+	if len(de.AppsPath) > 0 {
+		_ = runDesktopDBUpdate(de.AppsPath)
 	}
-	defer entryFile.Close()
 
-	if existingData, err = io.ReadAll(entryFile); err != nil {
-		return
+	if len(de.MimeType.Path) > 0 {
+		_ = runMimeUpdate(de.MimeType.Path)
 	}
 
-	if execLine, err = getExecLine(); err != nil {
+	if err != nil {
 		return
 	}
 
-	if match := execRegex.Find(existingData); match == nil || string(match) != execLine {
-		yes = true
-	}
-
-	if match := classRegex.Find(existingData); match == nil || string(match) != getStartupClassLine() {
-		yes = true
-	}
-
-	return
+	return removeManifestFile(de.getID())
 }
 
-func (de DesktopEntry) getEntryContent() (content string, err error) {
-	var execLine string
-
-	if execLine, err = getExecLine(); err != nil {
-		return
-	}
+// Uninstall removes whatever a previous [desktopEntry.DesktopEntry.Create] installed for name,
+// using its state manifest, without needing to reconstruct the original [desktopEntry.DesktopEntry]
+func Uninstall(name string) error {
+	return (&DesktopEntry{Name: name}).Remove()
+}
 
-	lines := []string{
-		"[Desktop Entry]",
-		"Type=" + de.Type,
-		"Name=" + de.Name,
-		execLine,
-		"Icon=" + de.getIconPath(),
-		getStartupClassLine(),
-	}
+func (de DesktopEntry) createPaths() (err error) {
+	paths := []string{de.AppsPath, de.IconsPath}
 
-	if de.Categories != "" {
-		lines = append(lines, "Categories="+de.Categories)
+	if len(de.MimeType.Path) > 0 {
+		paths = append(paths, de.MimeType.Path)
 	}
 
-	if de.Comment != "" {
-		lines = append(lines, "Comment="+de.Comment)
+	for _, path := range paths {
+		if _, err = os.Stat(path); os.IsNotExist(err) {
+			if err = os.MkdirAll(path, de.Perm); err != nil {
+				return
+			}
+			err = nil
+		} else if err != nil {
+			return
+		}
 	}
 
-	if len(de.MimeType.Type) > 0 {
-		lines = append(lines, "MimeType="+de.MimeType.Type)
-	}
+	return
+}
 
-	return strings.Join(lines, "\n"), nil
+func (de DesktopEntry) getID() string {
+	return strings.ToLower(de.Name)
 }
 
 func (de DesktopEntry) shouldCreateMimeType() (yes bool, err error) {
@@ -289,7 +306,7 @@ func (de DesktopEntry) getMimeTypePath() (string, error) {
 	typeChunks := strings.Split(de.MimeType.Type, "/")
 
 	if 1 >= len(typeChunks) {
-		return "", errors.New("invalid MimeType.Type")
+		return "", fmt.Errorf("invalid MimeType.Type")
 	}
 
 	return filepath.Join(de.MimeType.Path, "packages/"+typeChunks[1]+".xml"), nil
@@ -326,7 +343,7 @@ func (de DesktopEntry) createMimeType() (err error) {
 		return
 	}
 
-	_ = exec.Command(fmt.Sprintf(`update-mime-database "%s"`, de.MimeType.Path)).Run()
+	_ = runMimeUpdate(de.MimeType.Path)
 
 	return
 }
@@ -0,0 +1,172 @@
+package desktopEntry
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// createPlatformEntry materializes a minimal Foo.app bundle under ~/Applications, this is the
+// macOS backend for [desktopEntry.DesktopEntry.Create]. The returned path is the whole bundle,
+// used by [desktopEntry.DesktopEntry.Create] to track installed state
+func (de DesktopEntry) createPlatformEntry() (changed bool, paths []string, err error) {
+	var execPath string
+	var doUpdate = de.UpdateIfChanged
+
+	if execPath, err = os.Executable(); err != nil {
+		return
+	}
+
+	bundlePath := filepath.Join(getApplicationsDir(), de.Name+".app")
+	paths = []string{bundlePath}
+	contentsPath := filepath.Join(bundlePath, "Contents")
+	plistPath := filepath.Join(contentsPath, "Info.plist")
+	trampolinePath := filepath.Join(contentsPath, "MacOS", de.getID())
+	plistData := de.getInfoPlist()
+
+	if _, err = os.Stat(plistPath); err != nil && !os.IsNotExist(err) {
+		return
+	}
+
+	if _, err = os.Stat(plistPath); err == nil && doUpdate {
+		if doUpdate, err = de.shouldUpdateBundle(plistPath, trampolinePath, plistData, execPath); err != nil {
+			return
+		}
+	} else if os.IsNotExist(err) {
+		doUpdate, err = true, nil
+	} else {
+		return
+	}
+
+	if !doUpdate {
+		return
+	}
+
+	for _, dir := range []string{
+		filepath.Join(contentsPath, "MacOS"),
+		filepath.Join(contentsPath, "Resources"),
+	} {
+		if err = os.MkdirAll(dir, de.Perm); err != nil {
+			return
+		}
+	}
+
+	if err = os.WriteFile(plistPath, []byte(plistData), de.Perm); err != nil {
+		return
+	}
+
+	_ = os.Remove(trampolinePath)
+	if err = os.Symlink(execPath, trampolinePath); err != nil {
+		return
+	}
+
+	var icnsData []byte
+	if icnsData, err = encodeICNS(de.Icon); err != nil {
+		return
+	}
+
+	icnsPath := filepath.Join(contentsPath, "Resources", de.getID()+".icns")
+	if err = os.WriteFile(icnsPath, icnsData, de.Perm); err != nil {
+		return
+	}
+
+	changed = true
+	return
+}
+
+// shouldUpdateBundle reports whether the bundle needs rewriting: either the rendered plist no
+// longer matches (name/version/category/icon changed), or the MacOS trampoline symlink no
+// longer points at the current executable, since plistData itself never encodes the exec path
+func (de DesktopEntry) shouldUpdateBundle(plistPath, trampolinePath, plistData, execPath string) (yes bool, err error) {
+	var existing []byte
+
+	if existing, err = os.ReadFile(plistPath); err != nil {
+		return
+	}
+
+	if !bytes.Equal(existing, []byte(plistData)) {
+		return true, nil
+	}
+
+	target, readErr := os.Readlink(trampolinePath)
+	if readErr != nil {
+		return true, nil
+	}
+
+	return target != execPath, nil
+}
+
+func (de DesktopEntry) getInfoPlist() string {
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>CFBundleName</key>
+	<string>%s</string>
+	<key>CFBundleIdentifier</key>
+	<string>com.desktop-entry.%s</string>
+	<key>CFBundleVersion</key>
+	<string>%s</string>
+	<key>CFBundleIconFile</key>
+	<string>%s.icns</string>
+	<key>LSApplicationCategoryType</key>
+	<string>%s</string>
+	<key>CFBundleExecutable</key>
+	<string>%s</string>
+	<key>CFBundlePackageType</key>
+	<string>APPL</string>
+</dict>
+</plist>
+`, de.Name, de.getBundleIdentifier(), de.Version, de.getID(), de.getLSCategory(), de.getID())
+}
+
+var bundleIdentifierSanitizer = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// getBundleIdentifier sanitizes getID into the alphanumeric-and-hyphen subset macOS requires for
+// CFBundleIdentifier; a raw Name like "Desktop Entry" would otherwise produce the invalid
+// "com.desktop-entry.desktop entry" and LaunchServices can refuse to register the bundle
+func (de DesktopEntry) getBundleIdentifier() string {
+	id := bundleIdentifierSanitizer.ReplaceAllString(de.getID(), "-")
+	id = strings.Trim(id, "-")
+
+	if id == "" {
+		id = "app"
+	}
+
+	return id
+}
+
+// lsCategoryMap holds the freedesktop main categories with a well known Apple
+// LSApplicationCategoryType equivalent; anything else falls back to "utilities" rather than
+// synthesizing a UTI Apple doesn't define
+var lsCategoryMap = map[string]string{
+	"AudioVideo":  "public.app-category.entertainment",
+	"Audio":       "public.app-category.music",
+	"Video":       "public.app-category.video",
+	"Development": "public.app-category.developer-tools",
+	"Education":   "public.app-category.education",
+	"Game":        "public.app-category.games",
+	"Graphics":    "public.app-category.graphics-design",
+	"Office":      "public.app-category.productivity",
+	"Utility":     "public.app-category.utilities",
+}
+
+// getLSCategory maps the first freedesktop category to its Apple LSApplicationCategoryType via
+// lsCategoryMap, falling back to the generic "public.app-category.utilities" bucket Apple
+// expects every app to declare when there's no known equivalent
+func (de DesktopEntry) getLSCategory() string {
+	first, _, _ := strings.Cut(de.Categories, ";")
+
+	if category, ok := lsCategoryMap[first]; ok {
+		return category
+	}
+
+	return "public.app-category.utilities"
+}
+
+func getApplicationsDir() string {
+	return filepath.Join(os.Getenv("HOME"), "Applications")
+}
@@ -0,0 +1,283 @@
+package desktopEntry
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// createPlatformEntry writes (or updates) the freedesktop .desktop file and its icon(s), this
+// is the Linux backend for [desktopEntry.DesktopEntry.Create]. The returned paths are every
+// file it is responsible for, used by [desktopEntry.DesktopEntry.Create] to track installed
+// state
+func (de DesktopEntry) createPlatformEntry() (changed bool, paths []string, err error) {
+	var iconPaths []string
+
+	if iconPaths, err = de.createIcon(); err != nil {
+		return
+	}
+
+	entryPath := filepath.Join(de.AppsPath, de.getID()+".desktop")
+	paths = append([]string{entryPath}, iconPaths...)
+
+	changed, err = de.createEntry(entryPath)
+	return
+}
+
+func (de DesktopEntry) createEntry(entryPath string) (changed bool, err error) {
+	var entryData string
+	var doUpdate = de.UpdateIfChanged
+
+	if _, err = os.Stat(entryPath); err != nil && !os.IsNotExist(err) {
+		return
+	}
+
+	if _, err = os.Stat(entryPath); err == nil && doUpdate {
+		if doUpdate, err = de.shouldUpdate(entryPath); err != nil {
+			return
+		}
+	} else if os.IsNotExist(err) {
+		doUpdate, err = true, nil
+	} else {
+		return
+	}
+
+	if doUpdate {
+		if entryData, err = de.getEntryContent(); err != nil {
+			return
+		}
+
+		changed = true
+		err = os.WriteFile(entryPath, []byte(entryData), de.Perm)
+		_ = runDesktopDBUpdate(de.AppsPath)
+		return
+	}
+
+	return
+}
+
+var entryHashRegex = regexp.MustCompile(`X-DesktopEntry-Hash=[0-9a-f]+`)
+
+// shouldUpdate hashes the fully rendered entry and compares it against the X-DesktopEntry-Hash
+// stamped into the existing file, so any changed field (not just Exec= or StartupWMClass=)
+// triggers a rewrite
+func (de DesktopEntry) shouldUpdate(entryPath string) (yes bool, err error) {
+	var entryFile *os.File
+	var existingData []byte
+	var hash string
+
+	if entryFile, err = os.Open(entryPath); err != nil {
+		return
+	}
+	defer entryFile.Close()
+
+	if existingData, err = io.ReadAll(entryFile); err != nil {
+		return
+	}
+
+	if hash, err = de.getEntryHash(); err != nil {
+		return
+	}
+
+	match := entryHashRegex.Find(existingData)
+	yes = match == nil || string(match) != "X-DesktopEntry-Hash="+hash
+
+	return
+}
+
+func (de DesktopEntry) getEntryHash() (string, error) {
+	content, err := de.getRenderedEntryContent()
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// getEntryContent renders the main [Desktop Entry] group plus one [Desktop Action <id>] group
+// per de.Actions, and stamps the content with its own X-DesktopEntry-Hash so future
+// shouldUpdate calls can detect any changed field
+func (de DesktopEntry) getEntryContent() (content string, err error) {
+	var hash string
+
+	if content, err = de.getRenderedEntryContent(); err != nil {
+		return
+	}
+
+	if hash, err = de.getEntryHash(); err != nil {
+		return
+	}
+
+	return content + "\nX-DesktopEntry-Hash=" + hash, nil
+}
+
+func (de DesktopEntry) getRenderedEntryContent() (content string, err error) {
+	var execLine string
+	isApplication := de.Type != "Link"
+
+	if isApplication {
+		if execLine, err = getExecLine(); err != nil {
+			return
+		}
+	}
+
+	lines := []string{
+		"[Desktop Entry]",
+		"Type=" + de.Type,
+		"Name=" + de.Name,
+	}
+	lines = append(lines, de.getLocalizedLines("Name", func(l Locale) string { return l.Name })...)
+
+	if de.GenericName != "" {
+		lines = append(lines, "GenericName="+de.GenericName)
+	}
+	lines = append(lines, de.getLocalizedLines("GenericName", func(l Locale) string { return l.GenericName })...)
+
+	if de.Comment != "" {
+		lines = append(lines, "Comment="+de.Comment)
+	}
+	lines = append(lines, de.getLocalizedLines("Comment", func(l Locale) string { return l.Comment })...)
+
+	if len(de.Keywords) > 0 {
+		lines = append(lines, "Keywords="+joinSemicolon(de.Keywords))
+	}
+	lines = append(lines, de.getLocalizedLines("Keywords", func(l Locale) string { return joinSemicolon(l.Keywords) })...)
+
+	if isApplication {
+		lines = append(lines, execLine)
+
+		if de.TryExec != "" {
+			lines = append(lines, "TryExec="+de.TryExec)
+		}
+	}
+
+	lines = append(lines, "Icon="+de.getIconRef())
+
+	if isApplication {
+		lines = append(lines, getStartupClassLine())
+	}
+
+	if de.Categories != "" {
+		lines = append(lines, "Categories="+de.Categories)
+	}
+
+	if isApplication && de.Terminal {
+		lines = append(lines, "Terminal=true")
+	}
+
+	if de.NoDisplay {
+		lines = append(lines, "NoDisplay=true")
+	}
+
+	if de.Hidden {
+		lines = append(lines, "Hidden=true")
+	}
+
+	if len(de.OnlyShowIn) > 0 {
+		lines = append(lines, "OnlyShowIn="+joinSemicolon(de.OnlyShowIn))
+	}
+
+	if len(de.NotShowIn) > 0 {
+		lines = append(lines, "NotShowIn="+joinSemicolon(de.NotShowIn))
+	}
+
+	if de.SingleMainWindow {
+		lines = append(lines, "SingleMainWindow=true")
+	}
+
+	if de.PrefersNonDefaultGPU {
+		lines = append(lines, "PrefersNonDefaultGPU=true")
+	}
+
+	if de.DBusActivatable {
+		lines = append(lines, "DBusActivatable=true")
+	}
+
+	if de.Type == "Link" && de.URL != "" {
+		lines = append(lines, "URL="+de.URL)
+	}
+
+	if len(de.MimeType.Type) > 0 {
+		lines = append(lines, "MimeType="+de.MimeType.Type)
+	}
+
+	if len(de.Actions) > 0 {
+		ids := make([]string, len(de.Actions))
+		for i, action := range de.Actions {
+			ids[i] = getActionID(action.Name)
+		}
+		lines = append(lines, "Actions="+joinSemicolon(ids))
+	}
+
+	for _, action := range de.Actions {
+		lines = append(lines,
+			"",
+			"[Desktop Action "+getActionID(action.Name)+"]",
+			"Name="+action.Name,
+			"Exec="+action.Exec,
+		)
+		if action.Icon != "" {
+			lines = append(lines, "Icon="+action.Icon)
+		}
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// getLocalizedLines renders e.g. Name[de]=... for every configured locale that overrides field
+func (de DesktopEntry) getLocalizedLines(field string, get func(Locale) string) []string {
+	codes := make([]string, 0, len(de.Locales))
+	for code := range de.Locales {
+		codes = append(codes, code)
+	}
+	slices.Sort(codes)
+
+	lines := make([]string, 0, len(codes))
+	for _, code := range codes {
+		if value := get(de.Locales[code]); value != "" {
+			lines = append(lines, fmt.Sprintf("%s[%s]=%s", field, code, value))
+		}
+	}
+
+	return lines
+}
+
+func joinSemicolon(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return strings.Join(values, ";") + ";"
+}
+
+// getActionID derives a stable, spec-compliant action identifier from its human readable Name
+func getActionID(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() == 0 {
+		return "Action"
+	}
+	return b.String()
+}
+
+func getExecLine() (execPath string, err error) {
+	if execPath, err = os.Executable(); err != nil {
+		return
+	}
+
+	return fmt.Sprintf("Exec=sh -c '%s %%F'", execPath), nil
+}
+
+func getStartupClassLine() string {
+	return "StartupWMClass=" + filepath.Base(os.Args[0])
+}
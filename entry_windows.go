@@ -0,0 +1,162 @@
+package desktopEntry
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// createPlatformEntry installs a Start Menu .lnk shortcut, this is the Windows backend for
+// [desktopEntry.DesktopEntry.Create]. The returned paths are every file it is responsible for,
+// used by [desktopEntry.DesktopEntry.Create] to track installed state
+func (de DesktopEntry) createPlatformEntry() (changed bool, paths []string, err error) {
+	var execPath, iconPath, lnkPath string
+	var lnkData []byte
+	var doUpdate = de.UpdateIfChanged
+
+	if execPath, err = os.Executable(); err != nil {
+		return
+	}
+
+	if iconPath, err = de.createWindowsIcon(); err != nil {
+		return
+	}
+
+	lnkPath = filepath.Join(getStartMenuDir(), de.Name+".lnk")
+	paths = []string{lnkPath, iconPath}
+
+	if _, err = os.Stat(lnkPath); err != nil && !os.IsNotExist(err) {
+		return
+	}
+
+	if _, err = os.Stat(lnkPath); err == nil && doUpdate {
+		var existing []byte
+		if existing, err = os.ReadFile(lnkPath); err != nil {
+			return
+		}
+		doUpdate = !bytes.Contains(existing, []byte(execPath))
+	} else if os.IsNotExist(err) {
+		doUpdate, err = true, nil
+	} else {
+		return
+	}
+
+	if !doUpdate {
+		return
+	}
+
+	if lnkData, err = encodeLnk(execPath, iconPath); err != nil {
+		return
+	}
+
+	if err = os.MkdirAll(filepath.Dir(lnkPath), de.Perm); err != nil {
+		return
+	}
+
+	changed = true
+	err = os.WriteFile(lnkPath, lnkData, de.Perm)
+	return
+}
+
+// createWindowsIcon converts the source PNG into a multi-resolution .ico and stores it next to
+// the other desktop-entry assets under %LOCALAPPDATA%, skipping the re-encode when the file is
+// already in place so a Create() on every app startup doesn't needlessly redo it
+func (de DesktopEntry) createWindowsIcon() (iconPath string, err error) {
+	var icoData []byte
+
+	iconPath = filepath.Join(getWindowsIconsDir(), de.getID()+".ico")
+
+	if _, err = os.Stat(iconPath); err == nil {
+		return
+	} else if !os.IsNotExist(err) {
+		return
+	}
+
+	if icoData, err = encodeICO(de.Icon, []int{16, 32, 48, 256}); err != nil {
+		return
+	}
+
+	if err = os.MkdirAll(filepath.Dir(iconPath), de.Perm); err != nil {
+		return
+	}
+
+	err = os.WriteFile(iconPath, icoData, de.Perm)
+	return
+}
+
+func getStartMenuDir() string {
+	return filepath.Join(os.Getenv("APPDATA"), "Microsoft", "Windows", "Start Menu", "Programs")
+}
+
+func getWindowsIconsDir() string {
+	return filepath.Join(os.Getenv("LOCALAPPDATA"), "desktop-entry", "icons")
+}
+
+// encodeLnk builds a minimal but valid Shell Link (.lnk) binary pointing at targetPath, with
+// iconPath used as its IconLocation, see MS-SHLLINK
+func encodeLnk(targetPath, iconPath string) ([]byte, error) {
+	const (
+		hasLinkInfo     = 0x00000002
+		hasIconLocation = 0x00000040
+	)
+
+	var linkInfo bytes.Buffer
+
+	volumeID := bytes.NewBuffer(nil)
+	_ = binary.Write(volumeID, binary.LittleEndian, uint32(17)) // VolumeIDSize
+	_ = binary.Write(volumeID, binary.LittleEndian, uint32(3))  // DriveType: DRIVE_FIXED
+	_ = binary.Write(volumeID, binary.LittleEndian, uint32(0))  // DriveSerialNumber
+	_ = binary.Write(volumeID, binary.LittleEndian, uint32(16)) // VolumeLabelOffset
+	volumeID.WriteByte(0)                                       // empty, null terminated label
+
+	const linkInfoHeaderSize = 0x1C
+	volumeIDOffset := uint32(linkInfoHeaderSize)
+	localBasePathOffset := volumeIDOffset + uint32(volumeID.Len())
+	localBasePath := append([]byte(targetPath), 0)
+	commonPathSuffixOffset := localBasePathOffset + uint32(len(localBasePath))
+	commonPathSuffix := []byte{0}
+
+	linkInfoSize := commonPathSuffixOffset + uint32(len(commonPathSuffix))
+
+	_ = binary.Write(&linkInfo, binary.LittleEndian, linkInfoSize)
+	_ = binary.Write(&linkInfo, binary.LittleEndian, uint32(linkInfoHeaderSize))
+	_ = binary.Write(&linkInfo, binary.LittleEndian, uint32(1)) // VolumeIDAndLocalBasePath
+	_ = binary.Write(&linkInfo, binary.LittleEndian, volumeIDOffset)
+	_ = binary.Write(&linkInfo, binary.LittleEndian, localBasePathOffset)
+	_ = binary.Write(&linkInfo, binary.LittleEndian, uint32(0)) // no network share
+	_ = binary.Write(&linkInfo, binary.LittleEndian, commonPathSuffixOffset)
+	linkInfo.Write(volumeID.Bytes())
+	linkInfo.Write(localBasePath)
+	linkInfo.Write(commonPathSuffix)
+
+	var out bytes.Buffer
+
+	_ = binary.Write(&out, binary.LittleEndian, uint32(0x4C))                                                         // HeaderSize
+	out.Write([]byte{0x01, 0x14, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0xC0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x46}) // LinkCLSID
+
+	_ = binary.Write(&out, binary.LittleEndian, uint32(hasLinkInfo|hasIconLocation)) // LinkFlags
+	_ = binary.Write(&out, binary.LittleEndian, uint32(0x20))                        // FileAttributes: ARCHIVE
+	out.Write(make([]byte, 8*3))                                                     // Creation/Access/WriteTime
+	_ = binary.Write(&out, binary.LittleEndian, uint32(0))                           // FileSize
+	_ = binary.Write(&out, binary.LittleEndian, int32(0))                            // IconIndex
+	_ = binary.Write(&out, binary.LittleEndian, uint32(1))                           // ShowCommand: SW_SHOWNORMAL
+	out.Write(make([]byte, 2))                                                       // HotKey
+	out.Write(make([]byte, 2))                                                       // Reserved1
+	out.Write(make([]byte, 4))                                                       // Reserved2
+	out.Write(make([]byte, 4))                                                       // Reserved3
+
+	out.Write(linkInfo.Bytes())
+
+	iconBytes := []byte(iconPath)
+	if len(iconBytes) > 0xFFFF {
+		return nil, fmt.Errorf("icon path too long for .lnk IconLocation")
+	}
+	_ = binary.Write(&out, binary.LittleEndian, uint16(len(iconBytes)))
+	out.Write(iconBytes)
+
+	out.Write(make([]byte, 4)) // terminal ExtraData block
+
+	return out.Bytes(), nil
+}
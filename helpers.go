@@ -4,21 +4,8 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
-	"path/filepath"
 )
 
-func getExecLine() (execPath string, err error) {
-	if execPath, err = os.Executable(); err != nil {
-		return
-	}
-
-	return fmt.Sprintf("Exec=sh -c '%s %%F'", execPath), nil
-}
-
-func getStartupClassLine() string {
-	return "StartupWMClass=" + filepath.Base(os.Args[0])
-}
-
 func restart() (err error) {
 	var cmd *exec.Cmd
 
@@ -39,3 +26,21 @@ func restart() (err error) {
 	os.Exit(0)
 	return
 }
+
+// runMimeUpdate best-effort refreshes the shared-mime-info database, it is a no-op on
+// platforms (or systems) that don't ship the tool
+func runMimeUpdate(mimePath string) error {
+	if err := exec.Command("update-mime-database", mimePath).Run(); err != nil {
+		return fmt.Errorf("failed to run update-mime-database > %w", err)
+	}
+	return nil
+}
+
+// runDesktopDBUpdate best-effort refreshes the applications desktop database, it is a no-op on
+// platforms (or systems) that don't ship the tool
+func runDesktopDBUpdate(appsPath string) error {
+	if err := exec.Command("update-desktop-database", appsPath).Run(); err != nil {
+		return fmt.Errorf("failed to run update-desktop-database > %w", err)
+	}
+	return nil
+}
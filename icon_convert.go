@@ -0,0 +1,118 @@
+package desktopEntry
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/png"
+
+	"golang.org/x/image/draw"
+)
+
+// resizePNGQuality decodes src and returns a size x size PNG using Catmull-Rom interpolation,
+// used everywhere an icon gets resized since nearest-neighbor scaling looks noticeably blocky
+// at the sizes menus, docks and taskbars actually display
+func resizePNGQuality(src []byte, size int) ([]byte, error) {
+	img, _, err := image.Decode(bytes.NewReader(src))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode source icon > %w", err)
+	}
+
+	bounds := img.Bounds()
+	if bounds.Dx() == size && bounds.Dy() == size {
+		return src, nil
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Over, nil)
+
+	var out bytes.Buffer
+	if err = png.Encode(&out, dst); err != nil {
+		return nil, fmt.Errorf("failed to encode resized icon > %w", err)
+	}
+
+	return out.Bytes(), nil
+}
+
+// encodeICO packs the given PNG source into a multi-resolution Windows .ico, each entry is
+// stored as a plain PNG image which every Windows version since Vista accepts
+func encodeICO(src []byte, sizes []int) ([]byte, error) {
+	type icoImage struct {
+		size int
+		data []byte
+	}
+
+	images := make([]icoImage, 0, len(sizes))
+
+	for _, size := range sizes {
+		data, err := resizePNGQuality(src, size)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, icoImage{size: size, data: data})
+	}
+
+	var out bytes.Buffer
+
+	header := []uint16{0, 1, uint16(len(images))}
+	for _, v := range header {
+		_ = binary.Write(&out, binary.LittleEndian, v)
+	}
+
+	offset := uint32(6 + 16*len(images))
+	for _, img := range images {
+		dim := byte(img.size)
+		if img.size >= 256 {
+			dim = 0
+		}
+
+		entry := []any{dim, dim, byte(0), byte(0), uint16(1), uint16(32), uint32(len(img.data)), offset}
+		for _, v := range entry {
+			_ = binary.Write(&out, binary.LittleEndian, v)
+		}
+
+		offset += uint32(len(img.data))
+	}
+
+	for _, img := range images {
+		out.Write(img.data)
+	}
+
+	return out.Bytes(), nil
+}
+
+// encodeICNS packs the given PNG source into a macOS .icns, using the PNG-backed ic07/ic08/ic09
+// icon types (128x128, 256x256 and 512x512) which modern macOS reads directly
+func encodeICNS(src []byte) ([]byte, error) {
+	type entry struct {
+		osType string
+		size   int
+	}
+
+	entries := []entry{
+		{"ic07", 128},
+		{"ic08", 256},
+		{"ic09", 512},
+	}
+
+	var body bytes.Buffer
+
+	for _, e := range entries {
+		data, err := resizePNGQuality(src, e.size)
+		if err != nil {
+			return nil, err
+		}
+
+		body.WriteString(e.osType)
+		_ = binary.Write(&body, binary.BigEndian, uint32(len(data)+8))
+		body.Write(data)
+	}
+
+	var out bytes.Buffer
+	out.WriteString("icns")
+	_ = binary.Write(&out, binary.BigEndian, uint32(body.Len()+8))
+	out.Write(body.Bytes())
+
+	return out.Bytes(), nil
+}
@@ -0,0 +1,139 @@
+package desktopEntry
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+)
+
+// standardIconSizes are the pixel sizes most hicolor-theme consumers (GNOME, KDE, elementary)
+// expect an app icon to ship, used to backfill sizes when only one source image is given
+var standardIconSizes = []int{16, 22, 24, 32, 48, 64, 128, 256, 512}
+
+// createIcon installs the app icon(s), preferring the freedesktop Icon Theme layout under
+// ~/.local/share/icons/hicolor when Icons or IconSVG are set, and falling back to the legacy
+// single ~/.icons/<id>.png file otherwise. The returned paths are every icon file written
+func (de DesktopEntry) createIcon() (paths []string, err error) {
+	if de.hasThemedIcons() {
+		return de.createThemedIcons()
+	}
+
+	return de.createLegacyIcon()
+}
+
+// hasThemedIcons reports whether the caller opted into the Icon Theme layout
+func (de DesktopEntry) hasThemedIcons() bool {
+	return len(de.Icons) > 0 || len(de.IconSVG) > 0
+}
+
+// getIconRef is the value of the Icon= key: a bare theme name when themed icons are installed,
+// so launchers pick the right resolution per display, or the legacy absolute path otherwise
+func (de DesktopEntry) getIconRef() string {
+	if de.hasThemedIcons() {
+		return de.getID()
+	}
+
+	return de.getIconPath()
+}
+
+func (de DesktopEntry) getIconPath() string {
+	return filepath.Join(de.IconsPath, de.getID()+".png")
+}
+
+func (de DesktopEntry) createLegacyIcon() (paths []string, err error) {
+	var iconPath = de.getIconPath()
+
+	paths = []string{iconPath}
+
+	if _, err = os.Stat(iconPath); !os.IsNotExist(err) {
+		return
+	}
+
+	err = os.WriteFile(iconPath, de.Icon, de.Perm)
+	return
+}
+
+// createThemedIcons writes each configured size under hicolor, skipping any file that's
+// already in place so a Create() called on every app startup doesn't re-resize and rewrite
+// unchanged icons, or needlessly invalidate the icon cache
+func (de DesktopEntry) createThemedIcons() (paths []string, err error) {
+	var hicolor = getHicolorDir()
+	var changed bool
+
+	sizes := standardIconSizes
+	var source []byte
+
+	if len(de.Icons) != 1 {
+		sizes = make([]int, 0, len(de.Icons))
+		for size := range de.Icons {
+			sizes = append(sizes, size)
+		}
+		slices.Sort(sizes)
+	} else {
+		for _, data := range de.Icons {
+			source = data
+		}
+	}
+
+	for _, size := range sizes {
+		dir := filepath.Join(hicolor, fmt.Sprintf("%dx%d", size, size), "apps")
+		path := filepath.Join(dir, de.getID()+".png")
+		paths = append(paths, path)
+
+		var statErr error
+		if _, statErr = os.Stat(path); statErr == nil {
+			continue
+		} else if !os.IsNotExist(statErr) {
+			err = statErr
+			return
+		}
+
+		data, ok := de.Icons[size]
+		if !ok {
+			if data, err = resizePNGQuality(source, size); err != nil {
+				return
+			}
+		}
+
+		if err = os.MkdirAll(dir, de.Perm); err != nil {
+			return
+		}
+
+		if err = os.WriteFile(path, data, de.Perm); err != nil {
+			return
+		}
+
+		changed = true
+	}
+
+	if len(de.IconSVG) > 0 {
+		dir := filepath.Join(hicolor, "scalable", "apps")
+		path := filepath.Join(dir, de.getID()+".svg")
+		paths = append(paths, path)
+
+		if _, statErr := os.Stat(path); statErr != nil && !os.IsNotExist(statErr) {
+			err = statErr
+			return
+		} else if os.IsNotExist(statErr) {
+			if err = os.MkdirAll(dir, de.Perm); err != nil {
+				return
+			}
+			if err = os.WriteFile(path, de.IconSVG, de.Perm); err != nil {
+				return
+			}
+			changed = true
+		}
+	}
+
+	if changed {
+		_ = exec.Command("gtk-update-icon-cache", hicolor).Run()
+	}
+
+	return
+}
+
+func getHicolorDir() string {
+	return filepath.Join(os.Getenv("HOME"), ".local", "share", "icons", "hicolor")
+}
@@ -0,0 +1,74 @@
+package desktopEntry
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// manifest records every file the last [desktopEntry.DesktopEntry.Create] produced for a given
+// app id, so [desktopEntry.DesktopEntry.Remove] and later Create runs can clean up without
+// re-deriving paths from (possibly since-changed) settings
+type manifest struct {
+	Files []string `json:"files"`
+}
+
+func getManifestPath(id string) string {
+	return filepath.Join(os.Getenv("HOME"), ".local", "state", "desktop-entry", id+".json")
+}
+
+func loadManifest(id string) (m manifest, err error) {
+	var data []byte
+
+	if data, err = os.ReadFile(getManifestPath(id)); os.IsNotExist(err) {
+		return manifest{}, nil
+	} else if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(data, &m)
+	return
+}
+
+func saveManifest(id string, m manifest) (err error) {
+	var data []byte
+	var path = getManifestPath(id)
+
+	if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+
+	if data, err = json.MarshalIndent(m, "", "  "); err != nil {
+		return
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+func removeManifestFile(id string) error {
+	err := os.Remove(getManifestPath(id))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// syncManifest drops files a previous Create left behind but that are no longer part of
+// current, e.g. after MimeType.Type changed or a MIME association was dropped, then
+// persists current as the new manifest
+func (de DesktopEntry) syncManifest(current []string) (err error) {
+	var prev manifest
+
+	if prev, err = loadManifest(de.getID()); err != nil {
+		return
+	}
+
+	for _, old := range prev.Files {
+		if !slices.Contains(current, old) {
+			_ = os.RemoveAll(old)
+		}
+	}
+
+	return saveManifest(de.getID(), manifest{Files: current})
+}
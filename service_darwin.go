@@ -0,0 +1,7 @@
+package desktopEntry
+
+// createService is a no-op on macOS, DesktopEntry.Service targets the systemd/XDG autostart
+// stack only
+func (de DesktopEntry) createService() (changed bool, paths []string, err error) {
+	return
+}
@@ -0,0 +1,167 @@
+package desktopEntry
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// createService installs the app as a systemd --user unit, or, when only AutoStart is set,
+// drops the desktop entry into the XDG autostart directory. The returned path is whichever
+// file it is responsible for, used by [desktopEntry.DesktopEntry.Create] to track installed
+// state
+func (de DesktopEntry) createService() (changed bool, paths []string, err error) {
+	if de.Service.Enabled {
+		return de.createSystemdService()
+	}
+
+	return de.createAutostartEntry()
+}
+
+func (de DesktopEntry) createSystemdService() (changed bool, paths []string, err error) {
+	var unitPath = filepath.Join(getSystemdUserDir(), de.getID()+".service")
+	var execLine string
+	var doUpdate = de.UpdateIfChanged
+
+	paths = []string{unitPath}
+
+	if err = os.MkdirAll(filepath.Dir(unitPath), de.Perm); err != nil {
+		return
+	}
+
+	if execLine, err = getServiceExecLine(); err != nil {
+		return
+	}
+
+	if _, statErr := os.Stat(unitPath); statErr == nil && doUpdate {
+		if doUpdate, err = shouldUpdateUnit(unitPath, execLine); err != nil {
+			return
+		}
+	} else if os.IsNotExist(statErr) {
+		doUpdate = true
+	} else if statErr != nil {
+		err = statErr
+		return
+	}
+
+	if !doUpdate {
+		return
+	}
+
+	changed = true
+	if err = os.WriteFile(unitPath, []byte(de.getUnitContent(execLine)), de.Perm); err != nil {
+		return
+	}
+
+	_ = exec.Command("systemctl", "--user", "daemon-reload").Run()
+	_ = exec.Command("systemctl", "--user", "enable", "--now", de.getID()+".service").Run()
+
+	return
+}
+
+func (de DesktopEntry) createAutostartEntry() (changed bool, paths []string, err error) {
+	var autostartPath = filepath.Join(getAutostartDir(), de.getID()+".desktop")
+	var content string
+
+	if !de.Service.AutoStart {
+		return
+	}
+
+	paths = []string{autostartPath}
+
+	if content, err = de.getEntryContent(); err != nil {
+		return
+	}
+
+	content += "\nX-GNOME-Autostart-enabled=true"
+
+	if existing, readErr := os.ReadFile(autostartPath); readErr == nil && existing != nil && string(existing) == content {
+		return
+	} else if readErr != nil && !os.IsNotExist(readErr) {
+		err = readErr
+		return
+	}
+
+	if err = os.MkdirAll(filepath.Dir(autostartPath), de.Perm); err != nil {
+		return
+	}
+
+	changed = true
+	err = os.WriteFile(autostartPath, []byte(content), de.Perm)
+	return
+}
+
+func (de DesktopEntry) getUnitContent(execLine string) string {
+	description := de.Service.Description
+	if description == "" {
+		description = de.Comment
+	}
+
+	wantedBy := de.Service.WantedBy
+	if wantedBy == "" {
+		wantedBy = "default.target"
+	}
+
+	lines := []string{"[Unit]"}
+
+	if description != "" {
+		lines = append(lines, "Description="+description)
+	}
+
+	lines = append(lines, "", "[Service]", execLine)
+
+	if de.Service.Restart != "" {
+		lines = append(lines, "Restart="+de.Service.Restart)
+	}
+
+	lines = append(lines, "", "[Install]", "WantedBy="+wantedBy)
+
+	return strings.Join(lines, "\n")
+}
+
+func shouldUpdateUnit(unitPath, execLine string) (yes bool, err error) {
+	var unitFile *os.File
+	var execRegex *regexp.Regexp
+	var existingData []byte
+
+	if execRegex, err = regexp.Compile("ExecStart=.*"); err != nil {
+		return
+	}
+
+	if unitFile, err = os.Open(unitPath); err != nil {
+		return
+	}
+	defer unitFile.Close()
+
+	if existingData, err = io.ReadAll(unitFile); err != nil {
+		return
+	}
+
+	if match := execRegex.Find(existingData); match == nil || string(match) != execLine {
+		yes = true
+	}
+
+	return
+}
+
+func getServiceExecLine() (line string, err error) {
+	var execPath string
+
+	if execPath, err = os.Executable(); err != nil {
+		return
+	}
+
+	return fmt.Sprintf("ExecStart=%s", execPath), nil
+}
+
+func getSystemdUserDir() string {
+	return filepath.Join(os.Getenv("HOME"), ".config", "systemd", "user")
+}
+
+func getAutostartDir() string {
+	return filepath.Join(os.Getenv("HOME"), ".config", "autostart")
+}